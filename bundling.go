@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// bundlingTopN is how many top holders are fed into the funding-graph
+// analysis; going deeper than this costs an RPC fan-out per holder for
+// diminishing signal.
+const bundlingTopN = 10
+
+// maxFundingHops bounds how many funder-of-a-funder hops are walked back
+// from each top holder when looking for a shared upstream wallet. Two
+// holders funded by different wallets that are themselves funded by the
+// same wallet one hop further back are a very common bundling pattern,
+// so stopping at the direct (1-hop) funder misses it.
+const maxFundingHops = 3
+
+// maxFundingLookupConcurrency caps how many GetSignaturesForAddress/
+// GetTransaction calls run in parallel, to stay under mainnet-beta's
+// public endpoint rate limits.
+const maxFundingLookupConcurrency = 4
+
+var fundingLookupSemaphore = make(chan struct{}, maxFundingLookupConcurrency)
+
+// funderCacheTTL bounds how long a resolved funder is trusted before being
+// looked up again; funding wallets don't change, but this keeps the cache
+// from growing unbounded across a long-running bot process.
+const funderCacheTTL = 30 * time.Minute
+
+type funderCacheEntry struct {
+	funder    string
+	createdAt int64 // slot of the account's first transaction
+	expiresAt time.Time
+}
+
+var funderCache = struct {
+	mu sync.Mutex
+	m  map[string]funderCacheEntry
+}{m: make(map[string]funderCacheEntry)}
+
+// BundlingResult is the outcome of the funding-graph bundling detector,
+// replacing the old bare float score with enough detail to render a
+// meaningful "Bundle: X wallets funded by `abc...def`" line.
+type BundlingResult struct {
+	Score          float64
+	ClusterFunder  string
+	ClusterSize    int
+}
+
+// calculateBundlingScore inspects the top holders' funding history rather
+// than just eyeballing similar holding sizes, since amount-similarity alone
+// is trivial to defeat by varying buy sizes slightly. It combines:
+//
+//  1. the fraction of top holders that share a common funder within
+//     maxFundingHops hops (not just a direct, 1-hop funder match),
+//  2. temporal clustering (accounts created in the same slot window), and
+//  3. similar current holding sizes (the original heuristic, kept as a
+//     signal); the sizes of the holders' initial buys are not looked up.
+func calculateBundlingScore(ctx context.Context, holders []TokenHolder) BundlingResult {
+	n := len(holders)
+	if n < 2 {
+		return BundlingResult{}
+	}
+	if n > bundlingTopN {
+		n = bundlingTopN
+	}
+	top := holders[:n]
+
+	chains := make([][]string, n)
+	slots := make([]int64, n)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i, holder := range top {
+		wg.Add(1)
+		go func(i int, address string) {
+			defer wg.Done()
+			chain, slot := resolveFunderChain(ctx, address, maxFundingHops)
+			mu.Lock()
+			chains[i] = chain
+			slots[i] = slot
+			mu.Unlock()
+		}(i, holder.Address)
+	}
+	wg.Wait()
+
+	// For each ancestor wallet, count how many distinct top holders reach
+	// it within maxFundingHops — that's "fraction of top holders sharing
+	// a common funder within K hops", not just a direct 1-hop match.
+	holdersReachingAncestor := make(map[string]int)
+	for _, chain := range chains {
+		for _, ancestor := range chain {
+			holdersReachingAncestor[ancestor]++
+		}
+	}
+
+	biggestFunder, biggestCount := "", 0
+	for ancestor, count := range holdersReachingAncestor {
+		if count > biggestCount {
+			biggestFunder, biggestCount = ancestor, count
+		}
+	}
+
+	sharedFunderFraction := 0.0
+	if n > 0 {
+		sharedFunderFraction = float64(biggestCount) / float64(n)
+	}
+
+	temporalClusterFraction := temporalClusterFraction(slots)
+	similarityScore := initialBuySimilarity(top)
+
+	// Weighted blend: shared funders are the strongest bundling signal,
+	// temporal clustering corroborates it, and holding-size similarity is a
+	// weak, easily-faked tiebreaker.
+	score := 0.5*sharedFunderFraction + 0.3*temporalClusterFraction + 0.2*similarityScore
+	if score > 1 {
+		score = 1
+	}
+
+	return BundlingResult{
+		Score:         score,
+		ClusterFunder: biggestFunder,
+		ClusterSize:   biggestCount,
+	}
+}
+
+// slotWindowForClustering sets the slot window used for clustering: funding
+// slots are grouped into fixed buckets of slotWindowForClustering+1
+// consecutive slots, and accounts whose funding transactions fall in the same
+// bucket count as clustered. Two slots this close can still land in
+// neighbouring buckets. Funding txs that land in the same block are the
+// strongest possible evidence of a single bundling script.
+const slotWindowForClustering = 1
+
+func temporalClusterFraction(slots []int64) float64 {
+	counts := make(map[int64]int)
+	total := 0
+	for _, slot := range slots {
+		if slot == 0 {
+			continue
+		}
+		bucket := slot / (slotWindowForClustering + 1)
+		counts[bucket]++
+		total++
+	}
+	if total == 0 {
+		return 0
+	}
+	biggest := 0
+	for _, c := range counts {
+		if c > biggest {
+			biggest = c
+		}
+	}
+	return float64(biggest) / float64(total)
+}
+
+// initialBuySimilarity returns the fraction of holders after the first whose
+// current Amount is within 20% of the first holder's. It compares present
+// balances, not the amounts of the holders' initial buys.
+func initialBuySimilarity(holders []TokenHolder) float64 {
+	if len(holders) < 2 {
+		return 0
+	}
+	similar := 0
+	for i := 1; i < len(holders); i++ {
+		ratio := float64(holders[i].Amount) / float64(holders[0].Amount)
+		if ratio > 0.8 && ratio < 1.2 {
+			similar++
+		}
+	}
+	return float64(similar) / float64(len(holders)-1)
+}
+
+// formatBundlingRisk renders the bundling score plus the funder cluster
+// (when one was found) for the analysis embed.
+func formatBundlingRisk(analysis *TokenAnalysis) string {
+	line := fmt.Sprintf("Score: %.2f/1.0", analysis.BundlingScore)
+	if analysis.BundleFunder != "" && analysis.BundleSize > 1 {
+		line += fmt.Sprintf("\nBundle: %d wallets funded by `%s`", analysis.BundleSize, truncateAddress(analysis.BundleFunder))
+	}
+	return line
+}
+
+// resolveFunderChain walks up to maxHops funder-of-a-funder links starting
+// from address, returning the chain of ancestor wallets (nearest first) and
+// the slot of address's own direct funding transaction. It stops early on
+// a lookup error or if it loops back to an address already seen.
+func resolveFunderChain(ctx context.Context, address string, maxHops int) ([]string, int64) {
+	var chain []string
+	seen := map[string]bool{address: true}
+
+	current := address
+	var firstSlot int64
+	for hop := 0; hop < maxHops; hop++ {
+		funder, slot, err := resolveFunder(ctx, current)
+		if err != nil || funder == "" || seen[funder] {
+			break
+		}
+		if hop == 0 {
+			firstSlot = slot
+		}
+		chain = append(chain, funder)
+		seen[funder] = true
+		current = funder
+	}
+
+	return chain, firstSlot
+}
+
+// resolveFunder walks an account's transaction history back to its first
+// signature and returns the fee payer of that first transaction as the
+// wallet that funded it, along with the slot that transaction landed in.
+// The transaction's transfers are not inspected, so a wallet that sent the
+// funds without paying the fee is not the one returned.
+// Results are cached with a TTL and RPC fan-out is capped by
+// fundingLookupSemaphore to avoid tripping mainnet-beta rate limits.
+func resolveFunder(ctx context.Context, address string) (string, int64, error) {
+	funderCache.mu.Lock()
+	if entry, ok := funderCache.m[address]; ok && time.Now().Before(entry.expiresAt) {
+		funderCache.mu.Unlock()
+		return entry.funder, entry.createdAt, nil
+	}
+	funderCache.mu.Unlock()
+
+	fundingLookupSemaphore <- struct{}{}
+	defer func() { <-fundingLookupSemaphore }()
+
+	pubkey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return "", 0, err
+	}
+
+	firstSig, err := earliestSignature(ctx, pubkey)
+	if err != nil {
+		return "", 0, err
+	}
+	if firstSig == nil {
+		return "", 0, fmt.Errorf("no transaction history for %s", address)
+	}
+
+	txResp, err := client.GetTransaction(ctx, firstSig.Signature, &rpc.GetTransactionOpts{
+		Commitment: rpc.CommitmentFinalized,
+	})
+	if err != nil || txResp == nil || txResp.Transaction == nil {
+		return "", 0, fmt.Errorf("fetching first transaction for %s: %w", address, err)
+	}
+
+	tx, err := txResp.Transaction.GetTransaction()
+	if err != nil || tx == nil || len(tx.Message.AccountKeys) == 0 {
+		return "", 0, fmt.Errorf("decoding first transaction for %s: %w", address, err)
+	}
+
+	// Heuristic: the fee payer (first account key) of the account's very
+	// first transaction is, in the overwhelming majority of bundling
+	// scripts, the wallet that funded it.
+	funder := tx.Message.AccountKeys[0].String()
+	slot := int64(txResp.Slot)
+
+	funderCache.mu.Lock()
+	now := time.Now()
+	for cached, entry := range funderCache.m {
+		if now.After(entry.expiresAt) {
+			delete(funderCache.m, cached)
+		}
+	}
+	funderCache.m[address] = funderCacheEntry{funder: funder, createdAt: slot, expiresAt: now.Add(funderCacheTTL)}
+	funderCache.mu.Unlock()
+
+	return funder, slot, nil
+}
+
+// earliestSignature pages backward through an account's signature history
+// until it reaches the oldest one, which GetSignaturesForAddress doesn't
+// expose directly.
+func earliestSignature(ctx context.Context, pubkey solana.PublicKey) (*rpc.TransactionSignature, error) {
+	const pageSize = 1000
+	limit := pageSize
+
+	var oldest *rpc.TransactionSignature
+	opts := &rpc.GetSignaturesForAddressOpts{
+		Limit:      &limit,
+		Commitment: rpc.CommitmentFinalized,
+	}
+
+	for {
+		sigs, err := client.GetSignaturesForAddressWithOpts(ctx, pubkey, opts)
+		if err != nil {
+			return oldest, err
+		}
+		if len(sigs) == 0 {
+			break
+		}
+
+		oldest = sigs[len(sigs)-1]
+
+		if len(sigs) < pageSize {
+			break
+		}
+		opts.Before = oldest.Signature
+	}
+
+	return oldest, nil
+}
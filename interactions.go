@@ -0,0 +1,326 @@
+package main
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// slashCommands are registered on ready and migrate the old message-content
+// parsing in messageCreate to a proper Discord UI. The URL/raw-address
+// auto-detect path in messageCreate is kept as a fallback for users who
+// still paste addresses directly.
+var slashCommands = []*discordgo.ApplicationCommand{
+	{
+		Name:        "analyze",
+		Description: "Analyze a Solana token mint",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "address",
+				Description: "Token mint address",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "wallet",
+		Description: "Look up a Solana wallet's SOL balance",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "address",
+				Description: "Wallet address",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "watch",
+		Description: "Watch a token or wallet for changes in this channel",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "address",
+				Description: "Token or wallet address",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "holders",
+		Description: "Show the top holders of a Solana token",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "address",
+				Description: "Token mint address",
+				Required:    true,
+			},
+		},
+	},
+}
+
+// registerSlashCommands registers the bot's slash commands globally. Global
+// registration can take up to an hour to propagate on Discord's side; that
+// tradeoff is preferred here over per-guild registration so the bot works
+// the same way in every server it's added to.
+func registerSlashCommands(s *discordgo.Session) error {
+	_, err := s.ApplicationCommandBulkOverwrite(s.State.User.ID, "", slashCommands)
+	return err
+}
+
+func interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		handleSlashCommand(s, i)
+	case discordgo.InteractionMessageComponent:
+		handleMessageComponent(s, i)
+	}
+}
+
+func handleSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	address := ""
+	for _, opt := range data.Options {
+		if opt.Name == "address" {
+			address = opt.StringValue()
+		}
+	}
+
+	if !validateSolanaAddress(address) {
+		respondEphemeral(s, i, "That doesn't look like a valid Solana address.")
+		return
+	}
+
+	switch data.Name {
+	case "analyze", "holders":
+		analysis, err := analyzeToken(address)
+		if err != nil {
+			respondEphemeral(s, i, "Could not analyze that address as a token.")
+			return
+		}
+		embed := buildTokenAnalysisEmbed(address, analysis, 0, true)
+		respond(s, i, embed, buildTokenAnalysisComponents(address, 0, analysis, true))
+
+	case "wallet":
+		balance, err := getWalletBalance(address)
+		if err != nil {
+			respondEphemeral(s, i, "Could not look up that wallet.")
+			return
+		}
+		embed := &discordgo.MessageEmbed{
+			Title:       "Solana Wallet",
+			Description: "Address: `" + address + "`",
+			Color:       0x00FF00,
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "Balance", Value: formatTokenAmount(uint64(balance*1e9), 9) + " SOL"},
+			},
+		}
+		respond(s, i, embed, nil)
+
+	case "watch":
+		respondToWatch(s, i, address)
+	}
+}
+
+func respondToWatch(s *discordgo.Session, i *discordgo.InteractionCreate, address string) {
+	guildID := i.GuildID
+	existing, err := subStore.ForScope(guildID, i.ChannelID)
+	if err != nil {
+		log.Printf("Error listing subscriptions for guild %s: %v", guildID, err)
+		respondEphemeral(s, i, "Something went wrong, try again later.")
+		return
+	}
+	if len(existing) >= maxSubscriptionsPerGuild {
+		respondEphemeral(s, i, "This server already has the maximum number of watches.")
+		return
+	}
+	for _, sub := range existing {
+		if sub.Address == address {
+			respondEphemeral(s, i, "That address is already being watched.")
+			return
+		}
+	}
+
+	createdBy := ""
+	if i.Member != nil {
+		createdBy = i.Member.User.ID
+	} else if i.User != nil {
+		createdBy = i.User.ID
+	}
+
+	sub := &Subscription{
+		GuildID:   guildID,
+		ChannelID: i.ChannelID,
+		Address:   address,
+		CreatedBy: createdBy,
+	}
+	if analysis, err := analyzeToken(address); err == nil {
+		sub.Kind = SubscriptionToken
+		sub.LastAnalysis = analysis
+	} else if balance, err := getWalletBalance(address); err == nil {
+		sub.Kind = SubscriptionWallet
+		sub.LastBalance = balance
+	} else {
+		respondEphemeral(s, i, "Could not resolve that address as a token or wallet.")
+		return
+	}
+
+	if err := subStore.Add(sub); err != nil {
+		log.Printf("Error saving subscription: %v", err)
+		respondEphemeral(s, i, "Something went wrong, try again later.")
+		return
+	}
+	respondEphemeral(s, i, "Now watching `"+truncateAddress(address)+"` in this channel.")
+}
+
+// handleMessageComponent routes button/select interactions on an analysis
+// embed. CustomIDs are colon-separated: "<action>:<address>:<page>".
+func handleMessageComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.MessageComponentData()
+	parts := strings.Split(data.CustomID, ":")
+	if len(parts) < 2 {
+		return
+	}
+	action, address := parts[0], parts[1]
+
+	switch action {
+	case "refresh", "page":
+		page := 0
+		if len(parts) > 2 {
+			page, _ = strconv.Atoi(parts[2])
+		}
+		analysis, err := analyzeToken(address)
+		if err != nil {
+			respondEphemeral(s, i, "Could not refresh that analysis.")
+			return
+		}
+		embed := buildTokenAnalysisEmbed(address, analysis, page, true)
+		updateMessage(s, i, embed, buildTokenAnalysisComponents(address, page, analysis, true))
+
+	case "toggle2022":
+		// CustomID is "toggle2022:<address>:<page>:<shown>" (see
+		// buildTokenAnalysisComponents) — the toggle state is parts[3],
+		// not parts[2], which is always the hardcoded page placeholder.
+		shown := len(parts) > 3 && parts[3] == "1"
+		analysis, err := analyzeToken(address)
+		if err != nil {
+			respondEphemeral(s, i, "Could not refresh that analysis.")
+			return
+		}
+		embed := buildTokenAnalysisEmbed(address, analysis, 0, !shown)
+		updateMessage(s, i, embed, buildTokenAnalysisComponents(address, 0, analysis, !shown))
+
+	case "explorer":
+		if len(data.Values) == 0 {
+			return
+		}
+		for _, explorer := range explorers {
+			if explorer.Name == data.Values[0] {
+				respondEphemeral(s, i, "[View on "+explorer.Name+"]("+explorer.URL+address+")")
+				return
+			}
+		}
+	}
+}
+
+// buildTokenAnalysisComponents builds the button/select row attached to a
+// token analysis embed: holder pagination, a refresh button, an explorer
+// switcher, and (for Token-2022 mints) a details toggle.
+func buildTokenAnalysisComponents(address string, page int, analysis *TokenAnalysis, showToken2022 bool) []discordgo.MessageComponent {
+	totalPages := (len(analysis.TopHolders) + holdersPerPage - 1) / holdersPerPage
+
+	navRow := []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "Refresh",
+			Style:    discordgo.SecondaryButton,
+			CustomID: "refresh:" + address + ":" + strconv.Itoa(page),
+		},
+	}
+	if page > 0 {
+		navRow = append(navRow, discordgo.Button{
+			Label:    "Show holders 1-5",
+			Style:    discordgo.PrimaryButton,
+			CustomID: "page:" + address + ":0",
+		})
+	}
+	if page+1 < totalPages {
+		navRow = append(navRow, discordgo.Button{
+			Label:    holdersPageLabel(len(analysis.TopHolders), page+1),
+			Style:    discordgo.PrimaryButton,
+			CustomID: "page:" + address + ":" + strconv.Itoa(page+1),
+		})
+	}
+	if analysis.IsToken2022 {
+		toggleState := "0"
+		if showToken2022 {
+			toggleState = "1"
+		}
+		navRow = append(navRow, discordgo.Button{
+			Label:    "Toggle Token-2022 details",
+			Style:    discordgo.SecondaryButton,
+			CustomID: "toggle2022:" + address + ":0:" + toggleState,
+		})
+	}
+
+	explorerOptions := make([]discordgo.SelectMenuOption, 0, len(explorers))
+	for _, explorer := range explorers {
+		explorerOptions = append(explorerOptions, discordgo.SelectMenuOption{
+			Label: "View on " + explorer.Name,
+			Value: explorer.Name,
+		})
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: navRow},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				CustomID:    "explorer:" + address,
+				Placeholder: "Switch explorer",
+				Options:     explorerOptions,
+			},
+		}},
+	}
+}
+
+func respond(s *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to interaction: %v", err)
+	}
+}
+
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to interaction: %v", err)
+	}
+}
+
+func updateMessage(s *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+	if err != nil {
+		log.Printf("Error updating interaction message: %v", err)
+	}
+}
@@ -12,7 +12,9 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/Plezo/cryptobot/rpcpool"
 	"github.com/bwmarrin/discordgo"
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
@@ -21,10 +23,27 @@ import (
 // Bot configuration
 var (
 	Token    string
-	RpcURL   string = "https://api.mainnet-beta.solana.com" // Default RPC URL
-	client   *rpc.Client
+	RpcURLs  rpcURLFlag // Solana RPC URL(s), repeatable for multi-endpoint failover
+	client   *rpcpool.Pool
+
+	subscriptionsDBPath string
+	watchInterval       time.Duration
+	subStore            *SubscriptionStore
 )
 
+// rpcURLFlag implements flag.Value so `-rpc` can be passed more than once
+// to configure multi-endpoint failover in rpcpool.
+type rpcURLFlag []string
+
+func (f *rpcURLFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *rpcURLFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
 // Explorer links structure
 type ExplorerLink struct {
 	Name string
@@ -45,7 +64,25 @@ type TokenAnalysis struct {
 	TopHolders      []TokenHolder
 	InsiderPercent  float64
 	BundlingScore   float64
+	BundleFunder    string
+	BundleSize      int
 	SuspiciousFlags []string
+
+	// Enriched fields. MintAuthority and FreezeAuthority come from the
+	// parsed mint account (see mint.go) and may be overwritten by a
+	// third-party provider; PriceUSD, MarketCapUSD and LPLocked are set only
+	// by providers that have access to more than raw RPC exposes (see
+	// providers.go). Zero-valued when the active provider can't supply them.
+	MintAuthority   string
+	FreezeAuthority string
+	PriceUSD        float64
+	MarketCapUSD    float64
+	LPLocked        bool
+
+	// Token-2022 specific fields, populated when the mint is owned by the
+	// Token-2022 program (see mint.go).
+	IsToken2022 bool
+	Extensions  MintExtensions
 }
 
 var explorers = []ExplorerLink{
@@ -71,10 +108,12 @@ var solanaAddressPattern = regexp.MustCompile(`[1-9A-HJ-NP-Za-km-z]{32,44}`)
 
 func init() {
 	flag.StringVar(&Token, "t", "", "Bot Token")
-	flag.StringVar(&RpcURL, "rpc", "https://api.mainnet-beta.solana.com", "Solana RPC Url")
+	flag.Var(&RpcURLs, "rpc", "Solana RPC Url (repeatable for multi-endpoint failover, default https://api.mainnet-beta.solana.com)")
+	flag.StringVar(&subscriptionsDBPath, "subscriptions-db", "subscriptions.db", "Path to the subscriptions database")
+	flag.DurationVar(&watchInterval, "watch-interval", 60*time.Second, "How often to poll watched addresses")
 	flag.Parse()
 
-	client = rpc.New(RpcURL)
+	client = rpcpool.New(RpcURLs)
 }
 
 func main() {
@@ -91,6 +130,15 @@ func main() {
 	// Register handlers
 	dg.AddHandler(ready)
 	dg.AddHandler(messageCreate)
+	dg.AddHandler(interactionCreate)
+
+	// Open the subscription store and start the watch poller
+	subStore, err = openSubscriptionStore(subscriptionsDBPath)
+	if err != nil {
+		log.Fatal("Error opening subscriptions database:", err)
+	}
+	defer subStore.Close()
+	go pollSubscriptions(dg, watchInterval)
 
 	// Open websocket connection
 	err = dg.Open()
@@ -98,6 +146,10 @@ func main() {
 		log.Fatal("Error opening connection:", err)
 	}
 
+	if err := registerSlashCommands(dg); err != nil {
+		log.Printf("Error registering slash commands: %v", err)
+	}
+
 	// Wait for interrupt signal
 	fmt.Println("Bot is running. Press CTRL-C to exit.")
 	sc := make(chan os.Signal, 1)
@@ -117,6 +169,10 @@ func messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
 		return
 	}
 
+	if handleCommand(s, m) {
+		return
+	}
+
 	// Process URLs first
 	foundInURL := false
 	for _, explorer := range explorers {
@@ -240,7 +296,16 @@ func validateSolanaAddress(address string) bool {
 	return true
 }
 
+// analyzeToken produces a TokenAnalysis for address using whichever
+// AnalysisProvider is active (see providers.go): a configured third-party
+// API if one has credentials set, otherwise raw RPC.
 func analyzeToken(address string) (*TokenAnalysis, error) {
+	return activeProvider().AnalyzeToken(context.Background(), address)
+}
+
+// analyzeTokenFromRPC is the RPCProvider's implementation, and the base
+// that every other provider enriches before returning.
+func analyzeTokenFromRPC(ctx context.Context, address string) (*TokenAnalysis, error) {
 	pubkey, err := solana.PublicKeyFromBase58(address)
 	if err != nil {
 		return nil, err
@@ -248,25 +313,29 @@ func analyzeToken(address string) (*TokenAnalysis, error) {
 
 	// Get token mint info
 	mintAcc, err := client.GetAccountInfo(
-		context.Background(),
+		ctx,
 		pubkey,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse mint account data to get decimals
+	// Parse the mint account (classic SPL Token or Token-2022, including
+	// any TLV extensions) rather than peeking at a hardcoded offset.
+	var mintInfo *MintInfo
 	var decimals uint8
 	if mintAcc != nil && mintAcc.Value != nil {
-		mintData := mintAcc.Value.Data.GetBinary()
-		if len(mintData) >= 44 { // Minimum size for a mint account
-			decimals = mintData[44] // Decimals is stored at offset 44
+		mintInfo, err = parseMintAccount(mintAcc.Value.Owner, mintAcc.Value.Data.GetBinary())
+		if err != nil {
+			log.Printf("Error parsing mint account %s: %v", address, err)
+		} else {
+			decimals = mintInfo.Decimals
 		}
 	}
 
 	// Get all token accounts with commitment type
 	accounts, err := client.GetTokenLargestAccounts(
-		context.Background(),
+		ctx,
 		pubkey,
 		rpc.CommitmentFinalized,
 	)
@@ -310,13 +379,24 @@ func analyzeToken(address string) (*TokenAnalysis, error) {
 		insiderPercent += holders[i].Percent
 	}
 
+	bundling := calculateBundlingScore(ctx, holders)
+
 	analysis := &TokenAnalysis{
 		TotalSupply:    totalSupply,
 		Decimals:       decimals,
 		HolderCount:    len(holders),
-		TopHolders:     holders[:min(5, len(holders))],
+		TopHolders:     holders[:min(maxRenderedHolders, len(holders))],
 		InsiderPercent: insiderPercent,
-		BundlingScore:  calculateBundlingScore(holders),
+		BundlingScore:  bundling.Score,
+		BundleFunder:   bundling.ClusterFunder,
+		BundleSize:     bundling.ClusterSize,
+	}
+
+	if mintInfo != nil {
+		analysis.MintAuthority = mintInfo.MintAuthority
+		analysis.FreezeAuthority = mintInfo.FreezeAuthority
+		analysis.IsToken2022 = mintInfo.IsToken2022
+		analysis.Extensions = mintInfo.Extensions
 	}
 
 	if insiderPercent > 50 {
@@ -325,6 +405,18 @@ func analyzeToken(address string) (*TokenAnalysis, error) {
 	if analysis.BundlingScore > 0.7 {
 		analysis.SuspiciousFlags = append(analysis.SuspiciousFlags, "Possible bundling detected")
 	}
+	if analysis.FreezeAuthority != "" {
+		analysis.SuspiciousFlags = append(analysis.SuspiciousFlags, "Freeze authority present")
+	}
+	if analysis.Extensions.HasPermanentDelegate {
+		analysis.SuspiciousFlags = append(analysis.SuspiciousFlags, "Permanent delegate extension present")
+	}
+	if analysis.Extensions.HasTransferFee && analysis.Extensions.TransferFeeBasisPts > 0 {
+		analysis.SuspiciousFlags = append(analysis.SuspiciousFlags, fmt.Sprintf("Non-zero transfer fee (%.2f%%)", float64(analysis.Extensions.TransferFeeBasisPts)/100))
+	}
+	if analysis.Extensions.HasMutableMetadata {
+		analysis.SuspiciousFlags = append(analysis.SuspiciousFlags, "Mutable metadata (update authority can still change name/symbol/URI)")
+	}
 
 	return analysis, nil
 }
@@ -373,28 +465,30 @@ func addCommas(s string) string {
 }
 
 
-func calculateBundlingScore(holders []TokenHolder) float64 {
-	if len(holders) < 2 {
-		return 0
-	}
+// maxRenderedHolders is how many top holders are kept on a TokenAnalysis
+// for display; GetTokenLargestAccounts returns at most 20 so that's the
+// ceiling. holdersPerPage is how many of those are shown per embed page,
+// with the rest reachable one page at a time via the holder page buttons
+// (see interactions.go).
+const maxRenderedHolders = 20
+const holdersPerPage = 5
 
-	// Look for suspicious patterns in holder distribution
-	// 1. Similar-sized holdings
-	// 2. Regular distribution patterns
-	// 3. Recent creation of holder accounts
-
-	similarityScore := 0.0
-	for i := 1; i < len(holders) && i < 10; i++ {
-		ratio := float64(holders[i].Amount) / float64(holders[0].Amount)
-		if ratio > 0.8 && ratio < 1.2 {
-			similarityScore += 0.1
-		}
-	}
-
-	return similarityScore
+func sendTokenAnalysisEmbed(s *discordgo.Session, channelID, address string, analysis *TokenAnalysis, reference *discordgo.MessageReference) {
+	embed := buildTokenAnalysisEmbed(address, analysis, 0, true)
+	s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embed:      embed,
+		Components: buildTokenAnalysisComponents(address, 0, analysis, true),
+		Reference:  reference,
+	})
 }
 
-func sendTokenAnalysisEmbed(s *discordgo.Session, channelID, address string, analysis *TokenAnalysis, reference *discordgo.MessageReference) {
+// buildTokenAnalysisEmbed builds the embed shown for a token analysis
+// without sending it, so callers other than the reply path (e.g. the watch
+// poller and slash-command handlers) can reuse it. page selects which
+// holdersPerPage-sized slice of analysis.TopHolders to render. showToken2022
+// controls whether the "Token-2022 Extensions" field is included, so the
+// "Toggle Token-2022 details" button (see interactions.go) has an effect.
+func buildTokenAnalysisEmbed(address string, analysis *TokenAnalysis, page int, showToken2022 bool) *discordgo.MessageEmbed {
 	embed := &discordgo.MessageEmbed{
 		Title:       "Token Analysis",
 		Description: fmt.Sprintf("Analysis for token: `%s`", address),
@@ -409,8 +503,8 @@ func sendTokenAnalysisEmbed(s *discordgo.Session, channelID, address string, ana
 				Inline: false,
 			},
 			{
-				Name:   "Top Holders",
-				Value:  formatTopHoldersWithDecimals(analysis.TopHolders, analysis.Decimals),
+				Name:   fmt.Sprintf("Top Holders (%s)", holdersPageLabel(len(analysis.TopHolders), page)),
+				Value:  formatTopHoldersWithDecimals(pageHolders(analysis.TopHolders, page), analysis.Decimals, page*holdersPerPage),
 				Inline: false,
 			},
 			{
@@ -420,12 +514,32 @@ func sendTokenAnalysisEmbed(s *discordgo.Session, channelID, address string, ana
 			},
 			{
 				Name:   "Bundling Risk",
-				Value:  fmt.Sprintf("Score: %.2f/1.0", analysis.BundlingScore),
+				Value:  formatBundlingRisk(analysis),
 				Inline: false,
 			},
 		},
 	}
 
+	if analysis.IsToken2022 && showToken2022 {
+		mintLine := "Mint Authority: none"
+		if analysis.MintAuthority != "" {
+			mintLine = "Mint Authority: `" + truncateAddress(analysis.MintAuthority) + "`"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Token-2022 Extensions",
+			Value:  mintLine + "\n" + formatToken2022Extensions(analysis.Extensions),
+			Inline: false,
+		})
+	}
+
+	if analysis.PriceUSD > 0 || analysis.MarketCapUSD > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Market Data",
+			Value:  fmt.Sprintf("Price: $%.6f\nMarket Cap: $%s", analysis.PriceUSD, formatNumber(uint64(analysis.MarketCapUSD))),
+			Inline: false,
+		})
+	}
+
 	// Add warnings if any
 	if len(analysis.SuspiciousFlags) > 0 {
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
@@ -445,14 +559,14 @@ func sendTokenAnalysisEmbed(s *discordgo.Session, channelID, address string, ana
 		})
 	}
 
-	s.ChannelMessageSendEmbedReply(channelID, embed, reference)
+	return embed
 }
 
-func formatTopHoldersWithDecimals(holders []TokenHolder, decimals uint8) string {
+func formatTopHoldersWithDecimals(holders []TokenHolder, decimals uint8, startRank int) string {
 	var sb strings.Builder
 	for i, holder := range holders {
 		sb.WriteString(fmt.Sprintf("%d. `%s`: %s (%0.2f%%)\n",
-			i+1,
+			startRank+i+1,
 			truncateAddress(holder.Address),
 			formatTokenAmount(holder.Amount, decimals),
 			holder.Percent))
@@ -460,6 +574,28 @@ func formatTopHoldersWithDecimals(holders []TokenHolder, decimals uint8) string
 	return sb.String()
 }
 
+// pageHolders returns the holdersPerPage-sized slice of holders for page
+// (0-indexed), clamped to the available range.
+func pageHolders(holders []TokenHolder, page int) []TokenHolder {
+	start := page * holdersPerPage
+	if start >= len(holders) {
+		return nil
+	}
+	end := min(start+holdersPerPage, len(holders))
+	return holders[start:end]
+}
+
+// holdersPageLabel renders the "1-5" / "6-10" range shown in the Top
+// Holders field title for a given page.
+func holdersPageLabel(total, page int) string {
+	start := page*holdersPerPage + 1
+	end := min(start+holdersPerPage-1, total)
+	if start > end {
+		return fmt.Sprintf("%d-%d", start, start)
+	}
+	return fmt.Sprintf("%d-%d", start, end)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/token"
+)
+
+// zeroPubkey is the OptionalNonZeroPubkey "none" sentinel used throughout
+// Token-2022's TLV extensions.
+var zeroPubkey [32]byte
+
+// token2022ProgramID is the SPL Token-2022 program, which extends the
+// classic SPL Token mint layout with a trailing TLV extension section. Use
+// the solana-go SDK's own constant rather than hand-typing the base58
+// address again — a single mistyped character here would make every mint
+// silently decode as a classic token, and go vet/build can't catch it since
+// it would only surface as a wrong bool, not a compile error.
+var token2022ProgramID = solana.Token2022ProgramID
+
+// splTokenMintSize is the fixed size of the base SPL Token Mint account,
+// shared by both the classic token program and Token-2022.
+const splTokenMintSize = 82
+
+// splTokenAccountSize is Account::LEN. Token-2022 pads a Mint out to this
+// size (not splTokenMintSize) before appending the one-byte AccountType
+// discriminator and the TLV extension section, matching the layout used by
+// gagliardetto/solana-go's own token2022.ParseMintWithExtensions.
+const splTokenAccountSize = 165
+
+// Token-2022 extension type tags, as laid out in the TLV section that
+// follows the base mint account. These match the ExtensionType enum in
+// gagliardetto/solana-go's programs/token-2022 package — note that 13 is
+// NonTransferableAccount (an account-level extension, not this one) and 8
+// is MemoTransfer, so don't reuse those tags for the mint-level extensions
+// below. Only the ones relevant to rug-risk assessment are enumerated here.
+const (
+	extensionTransferFeeConfig     uint16 = 1
+	extensionMintCloseAuthority    uint16 = 3
+	extensionInterestBearingConfig uint16 = 10
+	extensionPermanentDelegate     uint16 = 12
+	extensionNonTransferable       uint16 = 9
+	extensionMetadataPointer       uint16 = 18
+	extensionTokenMetadata         uint16 = 19
+)
+
+// tokenMetadataUpdateAuthorityOffset is where the update_authority
+// OptionalNonZeroPubkey sits at the start of a TokenMetadata TLV value (see
+// spl_token_metadata_interface::state::TokenMetadata). The all-zero pubkey
+// is the sentinel for "no authority"; anything else means the name/symbol/
+// URI can still be rewritten after mint, which is what callers actually
+// mean by "mutable metadata".
+const tokenMetadataUpdateAuthorityOffset = 0
+
+// TransferFeeConfig's TLV value (see spl_token_2022's transfer_fee
+// extension) is two 32-byte optional authority pubkeys, an 8-byte withheld
+// amount, then two 18-byte TransferFee records (epoch:8 + maximum_fee:8 +
+// transfer_fee_basis_points:2) — "older" first, then "newer":
+//
+//	transfer_fee_config_authority 32
+//	withdraw_withheld_authority   32
+//	withheld_amount                8
+//	older_transfer_fee            18  (epoch 8, maximum_fee 8, basis_points 2)
+//	newer_transfer_fee            18  (epoch 8, maximum_fee 8, basis_points 2)
+const (
+	transferFeeConfigOlderFeeOffset = 32 + 32 + 8
+	transferFeeConfigNewerFeeOffset = transferFeeConfigOlderFeeOffset + 18
+	transferFeeBasisPointsOffset    = 8 + 8 // within a single TransferFee record
+	newerTransferFeeBpsOffset       = transferFeeConfigNewerFeeOffset + transferFeeBasisPointsOffset
+	transferFeeConfigSize           = transferFeeConfigNewerFeeOffset + 18
+)
+
+// MintInfo is the fully decoded result of parsing a (possibly Token-2022)
+// mint account, replacing the old single-byte decimals peek.
+type MintInfo struct {
+	Decimals        uint8
+	Supply          uint64
+	MintAuthority   string
+	FreezeAuthority string
+	IsToken2022     bool
+	Extensions      MintExtensions
+}
+
+// MintExtensions holds the Token-2022 TLV extensions relevant to rug risk.
+// Every field defaults to its "safe" zero value when the extension isn't
+// present, so callers can render warnings purely off these flags.
+type MintExtensions struct {
+	HasTransferFee      bool
+	TransferFeeBasisPts uint16
+	HasPermanentDelegate bool
+	IsNonTransferable   bool
+	IsInterestBearing   bool
+	HasMetadataPointer  bool
+	HasMutableMetadata  bool
+}
+
+// parseMintAccount deserializes raw mint account data, whether it belongs
+// to the classic SPL Token program or Token-2022, and parses any trailing
+// TLV extensions in the latter case.
+func parseMintAccount(owner solana.PublicKey, data []byte) (*MintInfo, error) {
+	if len(data) < splTokenMintSize {
+		return nil, fmt.Errorf("mint account data too short: %d bytes", len(data))
+	}
+
+	var mint token.Mint
+	decoder := bin.NewBinDecoder(data[:splTokenMintSize])
+	if err := decoder.Decode(&mint); err != nil {
+		return nil, fmt.Errorf("decoding mint account: %w", err)
+	}
+
+	info := &MintInfo{
+		Decimals:    mint.Decimals,
+		Supply:      mint.Supply,
+		IsToken2022: owner.Equals(token2022ProgramID),
+	}
+	if mint.MintAuthority != nil {
+		info.MintAuthority = mint.MintAuthority.String()
+	}
+	if mint.FreezeAuthority != nil {
+		info.FreezeAuthority = mint.FreezeAuthority.String()
+	}
+
+	if info.IsToken2022 && len(data) > splTokenAccountSize {
+		info.Extensions = parseToken2022Extensions(data[splTokenAccountSize:])
+	}
+
+	return info, nil
+}
+
+// parseToken2022Extensions walks the TLV section that Token-2022 appends
+// after the base mint layout (skipping the one-byte AccountType
+// discriminator) and returns the flags that matter for rug-risk warnings.
+// Unknown or malformed entries are skipped rather than treated as fatal,
+// since new extension types are added to the spec over time.
+func parseToken2022Extensions(tail []byte) MintExtensions {
+	var ext MintExtensions
+	if len(tail) < 1 {
+		return ext
+	}
+
+	buf := tail[1:] // skip AccountType byte
+	for len(buf) >= 4 {
+		extType := binary.LittleEndian.Uint16(buf[0:2])
+		extLen := binary.LittleEndian.Uint16(buf[2:4])
+		buf = buf[4:]
+		if int(extLen) > len(buf) {
+			break
+		}
+		value := buf[:extLen]
+		buf = buf[extLen:]
+
+		switch extType {
+		case extensionTransferFeeConfig:
+			ext.HasTransferFee = true
+			if len(value) >= transferFeeConfigSize {
+				// newerTransferFeeBpsOffset points at the basis-points
+				// field of "newer_transfer_fee" — the fee that is (or is
+				// about to become) active, as opposed to "older_transfer_fee",
+				// which is what's still in effect until its epoch arrives.
+				ext.TransferFeeBasisPts = binary.LittleEndian.Uint16(value[newerTransferFeeBpsOffset : newerTransferFeeBpsOffset+2])
+			}
+		case extensionPermanentDelegate:
+			ext.HasPermanentDelegate = true
+		case extensionNonTransferable:
+			ext.IsNonTransferable = true
+		case extensionInterestBearingConfig:
+			ext.IsInterestBearing = true
+		case extensionMetadataPointer:
+			ext.HasMetadataPointer = true
+		case extensionTokenMetadata:
+			if len(value) >= tokenMetadataUpdateAuthorityOffset+32 {
+				authority := value[tokenMetadataUpdateAuthorityOffset : tokenMetadataUpdateAuthorityOffset+32]
+				if !bytes.Equal(authority, zeroPubkey[:]) {
+					ext.HasMutableMetadata = true
+				}
+			}
+		}
+	}
+
+	return ext
+}
+
+// formatToken2022Extensions renders the extensions relevant to rug risk as
+// a short bullet list for the analysis embed.
+func formatToken2022Extensions(ext MintExtensions) string {
+	var lines []string
+	if ext.HasTransferFee {
+		lines = append(lines, fmt.Sprintf("• Transfer fee: %.2f%%", float64(ext.TransferFeeBasisPts)/100))
+	}
+	if ext.HasPermanentDelegate {
+		lines = append(lines, "• Permanent delegate present")
+	}
+	if ext.IsNonTransferable {
+		lines = append(lines, "• Non-transferable (soulbound)")
+	}
+	if ext.IsInterestBearing {
+		lines = append(lines, "• Interest-bearing")
+	}
+	if ext.HasMetadataPointer {
+		lines = append(lines, "• Metadata pointer set")
+	}
+	if ext.HasMutableMetadata {
+		lines = append(lines, "• Metadata is mutable (update authority set)")
+	}
+	if len(lines) == 0 {
+		return "• No notable extensions"
+	}
+	return strings.Join(lines, "\n")
+}
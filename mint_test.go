@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// buildToken2022MintBlob constructs a synthetic Token-2022 mint account:
+// the 82-byte base Mint layout, padded out to Account::LEN (165 bytes),
+// followed by the AccountType byte and a transfer-fee-config TLV entry.
+func buildToken2022MintBlob(decimals uint8, transferFeeBasisPts uint16) []byte {
+	data := make([]byte, splTokenAccountSize)
+
+	// mint_authority: COption<Pubkey> = None
+	binary.LittleEndian.PutUint32(data[0:4], 0)
+	// supply: u64
+	binary.LittleEndian.PutUint64(data[36:44], 1_000_000)
+	// decimals: u8
+	data[44] = decimals
+	// is_initialized: bool
+	data[45] = 1
+	// freeze_authority: COption<Pubkey> = None
+	binary.LittleEndian.PutUint32(data[46:50], 0)
+
+	// AccountType byte (1 = Mint), then one TLV extension.
+	data = append(data, 1)
+
+	header := make([]byte, 4)
+	binary.LittleEndian.PutUint16(header[0:2], extensionTransferFeeConfig)
+
+	// TransferFeeConfig value: two 32-byte authority pubkeys, an 8-byte
+	// withheld amount, then older_transfer_fee and newer_transfer_fee (each
+	// epoch:8 + maximum_fee:8 + transfer_fee_basis_points:2). The basis
+	// points that should actually be read back are the ones in
+	// newer_transfer_fee, at newerTransferFeeBpsOffset.
+	value := make([]byte, transferFeeConfigSize)
+	binary.LittleEndian.PutUint16(value[newerTransferFeeBpsOffset:newerTransferFeeBpsOffset+2], transferFeeBasisPts)
+	binary.LittleEndian.PutUint16(header[2:4], uint16(len(value)))
+
+	data = append(data, header...)
+	data = append(data, value...)
+
+	return data
+}
+
+func TestParseMintAccountToken2022TransferFee(t *testing.T) {
+	data := buildToken2022MintBlob(6, 250) // 2.50%
+
+	info, err := parseMintAccount(token2022ProgramID, data)
+	if err != nil {
+		t.Fatalf("parseMintAccount returned error: %v", err)
+	}
+
+	if !info.IsToken2022 {
+		t.Fatal("expected IsToken2022 to be true for a Token-2022-owned mint")
+	}
+	if info.Decimals != 6 {
+		t.Fatalf("expected decimals 6, got %d", info.Decimals)
+	}
+	if !info.Extensions.HasTransferFee {
+		t.Fatal("expected HasTransferFee to be true, extension parsing likely stopped at the wrong offset")
+	}
+	if info.Extensions.TransferFeeBasisPts != 250 {
+		t.Fatalf("expected transfer fee of 250 basis points, got %d", info.Extensions.TransferFeeBasisPts)
+	}
+}
+
+func TestParseMintAccountClassicTokenHasNoExtensions(t *testing.T) {
+	data := buildToken2022MintBlob(9, 0)[:splTokenMintSize]
+
+	classicProgramID := solana.MustPublicKeyFromBase58("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	info, err := parseMintAccount(classicProgramID, data)
+	if err != nil {
+		t.Fatalf("parseMintAccount returned error: %v", err)
+	}
+
+	if info.IsToken2022 {
+		t.Fatal("expected IsToken2022 to be false for the classic token program")
+	}
+	if info.Extensions.HasTransferFee {
+		t.Fatal("classic SPL Token mints have no TLV extensions to parse")
+	}
+}
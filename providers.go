@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// AnalysisProvider is implemented by each backend capable of producing a
+// TokenAnalysis for an address. RPCProvider talks to the Solana RPC
+// endpoint directly; the others call out to third-party indexers that can
+// offer richer data (full holder counts, creation time, mint/freeze
+// authority, LP lock status, price) than raw RPC exposes.
+type AnalysisProvider interface {
+	Name() string
+	AnalyzeToken(ctx context.Context, address string) (*TokenAnalysis, error)
+}
+
+// providerRegistry holds every provider available to the bot, in priority
+// order: the first one with its required configuration present is used.
+// New providers are added here rather than by editing analyzeToken.
+var providerRegistry []AnalysisProvider
+
+func registerProvider(p AnalysisProvider) {
+	providerRegistry = append(providerRegistry, p)
+}
+
+func init() {
+	if key := os.Getenv("HELIUS_API_KEY"); key != "" {
+		registerProvider(&HeliusProvider{APIKey: key})
+	}
+	if key := os.Getenv("BIRDEYE_API_KEY"); key != "" {
+		registerProvider(&BirdeyeProvider{APIKey: key})
+	}
+	if key := os.Getenv("SOLSCAN_API_KEY"); key != "" {
+		registerProvider(&SolscanProvider{APIKey: key})
+	}
+	// The raw-RPC provider requires no configuration, so it always goes
+	// last as the universal fallback.
+	registerProvider(&RPCProvider{})
+}
+
+// activeProvider returns the first configured provider, which is always at
+// least the RPCProvider fallback.
+func activeProvider() AnalysisProvider {
+	return providerRegistry[0]
+}
+
+// getFromAPI fetches a URL with a bounded timeout and verifies the response
+// is JSON before handing the body back, so a misconfigured endpoint that
+// returns an HTML error page fails fast with a clear error.
+func getFromAPI(ctx context.Context, url string, headers map[string]string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err == nil && mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+			return nil, fmt.Errorf("unexpected content-type %q from %s", mediaType, url)
+		}
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// getTypeFromAPI fetches and JSON-decodes a response into T, so adding a
+// new provider is a matter of defining its response struct and calling
+// this with the right URL/headers rather than hand-rolling decode logic.
+func getTypeFromAPI[T any](ctx context.Context, url string, headers map[string]string) (T, error) {
+	var out T
+	body, err := getFromAPI(ctx, url, headers)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return out, fmt.Errorf("decoding response from %s: %w", url, err)
+	}
+	return out, nil
+}
+
+// RPCProvider is the original, always-available backend: it derives a
+// TokenAnalysis purely from raw Solana RPC calls.
+type RPCProvider struct{}
+
+func (p *RPCProvider) Name() string { return "rpc" }
+
+func (p *RPCProvider) AnalyzeToken(ctx context.Context, address string) (*TokenAnalysis, error) {
+	return analyzeTokenFromRPC(ctx, address)
+}
+
+// SolscanProvider pulls enriched token data (holder counts beyond the top
+// 20, mint/freeze authorities, price, market cap) from the Solscan Pro API.
+// The creation time in the response is decoded but not copied onto the
+// analysis, and LP lock status is not fetched.
+type SolscanProvider struct {
+	APIKey string
+}
+
+func (p *SolscanProvider) Name() string { return "solscan" }
+
+type solscanTokenMetaResponse struct {
+	Data struct {
+		Holder          int     `json:"holder"`
+		CreatedTime     int64   `json:"created_time"`
+		MintAuthority   string  `json:"mint_authority"`
+		FreezeAuthority string  `json:"freeze_authority"`
+		PriceUSD        float64 `json:"price_usdt"`
+		MarketCapUSD    float64 `json:"market_cap"`
+	} `json:"data"`
+}
+
+func (p *SolscanProvider) AnalyzeToken(ctx context.Context, address string) (*TokenAnalysis, error) {
+	analysis, err := analyzeTokenFromRPC(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://pro-api.solscan.io/v2.0/token/meta?address=%s", address)
+	meta, err := getTypeFromAPI[solscanTokenMetaResponse](ctx, url, map[string]string{"token": p.APIKey})
+	if err != nil {
+		// Enrichment is best-effort: fall back to the plain RPC analysis
+		// rather than failing the whole lookup.
+		return analysis, nil
+	}
+
+	if meta.Data.Holder > analysis.HolderCount {
+		analysis.HolderCount = meta.Data.Holder
+	}
+	analysis.MintAuthority = meta.Data.MintAuthority
+	analysis.FreezeAuthority = meta.Data.FreezeAuthority
+	analysis.PriceUSD = meta.Data.PriceUSD
+	analysis.MarketCapUSD = meta.Data.MarketCapUSD
+	return analysis, nil
+}
+
+// HeliusProvider enriches analysis using the Helius DAS/token APIs.
+type HeliusProvider struct {
+	APIKey string
+}
+
+func (p *HeliusProvider) Name() string { return "helius" }
+
+type heliusAssetResponse struct {
+	Result struct {
+		Authorities []struct {
+			Address string   `json:"address"`
+			Scopes  []string `json:"scopes"`
+		} `json:"authorities"`
+		TokenInfo struct {
+			Supply     uint64  `json:"supply"`
+			Decimals   uint8   `json:"decimals"`
+			PriceInfo  float64 `json:"price_usdt"`
+		} `json:"token_info"`
+	} `json:"result"`
+}
+
+func (p *HeliusProvider) AnalyzeToken(ctx context.Context, address string) (*TokenAnalysis, error) {
+	analysis, err := analyzeTokenFromRPC(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://mainnet.helius-rpc.com/?api-key=%s", p.APIKey)
+	asset, err := getTypeFromAPI[heliusAssetResponse](ctx, url, nil)
+	if err != nil {
+		return analysis, nil
+	}
+
+	for _, authority := range asset.Result.Authorities {
+		for _, scope := range authority.Scopes {
+			switch scope {
+			case "mint":
+				analysis.MintAuthority = authority.Address
+			case "freeze":
+				analysis.FreezeAuthority = authority.Address
+			}
+		}
+	}
+	analysis.PriceUSD = asset.Result.TokenInfo.PriceInfo
+	return analysis, nil
+}
+
+// BirdeyeProvider enriches analysis using the Birdeye public API, mainly
+// for price and market-cap data that raw RPC has no concept of.
+type BirdeyeProvider struct {
+	APIKey string
+}
+
+func (p *BirdeyeProvider) Name() string { return "birdeye" }
+
+type birdeyeOverviewResponse struct {
+	Data struct {
+		Price     float64 `json:"price"`
+		MC        float64 `json:"mc"`
+		LiquidityLocked bool `json:"liquidityLocked"`
+	} `json:"data"`
+}
+
+func (p *BirdeyeProvider) AnalyzeToken(ctx context.Context, address string) (*TokenAnalysis, error) {
+	analysis, err := analyzeTokenFromRPC(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://public-api.birdeye.so/defi/token_overview?address=%s", address)
+	overview, err := getTypeFromAPI[birdeyeOverviewResponse](ctx, url, map[string]string{"X-API-KEY": p.APIKey})
+	if err != nil {
+		return analysis, nil
+	}
+
+	analysis.PriceUSD = overview.Data.Price
+	analysis.MarketCapUSD = overview.Data.MC
+	analysis.LPLocked = overview.Data.LiquidityLocked
+	return analysis, nil
+}
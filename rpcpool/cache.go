@@ -0,0 +1,84 @@
+package rpcpool
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds memory use; once exceeded, the least-recently-used
+// entry is evicted regardless of whether its TTL has expired yet.
+const maxCacheEntries = 2048
+
+func cacheKey(method string, params ...string) string {
+	return method + "|" + strings.Join(params, "|")
+}
+
+type cacheEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// ttlCache is an LRU cache with a per-entry expiry, keyed by
+// (method, params, commitment) via cacheKey. Entries past their TTL are
+// treated as misses but aren't proactively swept; they're cleaned up
+// lazily on the next get/set or evicted once the cache is full.
+type ttlCache struct {
+	mu       sync.Mutex
+	ll       *list.List
+	elements map[string]*list.Element
+}
+
+func newTTLCache() *ttlCache {
+	return &ttlCache{
+		ll:       list.New(),
+		elements: make(map[string]*list.Element),
+	}
+}
+
+func (c *ttlCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.elements[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.ll.Remove(el)
+		delete(c.elements, key)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	return entry.value, true
+}
+
+func (c *ttlCache) set(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.elements[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.value = value
+		entry.expiresAt = time.Now().Add(ttl)
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	entry := &cacheEntry{key: key, value: value, expiresAt: time.Now().Add(ttl)}
+	el := c.ll.PushFront(entry)
+	c.elements[key] = el
+
+	for c.ll.Len() > maxCacheEntries {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.elements, oldest.Value.(*cacheEntry).key)
+	}
+}
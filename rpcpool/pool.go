@@ -0,0 +1,317 @@
+// Package rpcpool wraps one or more Solana RPC endpoints behind a single
+// client, adding weighted round-robin failover, exponential backoff on
+// 429/5xx responses, and a small TTL cache so a busy Discord channel
+// doesn't turn every message into two or more uncached RPCs against a
+// rate-limited public endpoint.
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// DefaultWeight is used for every endpoint unless a caller configures
+// otherwise; all endpoints are treated equally until proven unhealthy.
+const DefaultWeight = 1
+
+// maxAttempts bounds how many endpoints a single call will try before
+// giving up, so a call never loops forever if every endpoint is down.
+const maxAttempts = 4
+
+// baseBackoff and maxBackoff bound the exponential backoff applied to an
+// endpoint after a 429/5xx response.
+const baseBackoff = 500 * time.Millisecond
+const maxBackoff = 30 * time.Second
+
+type endpoint struct {
+	url    string
+	weight int
+	client *rpc.Client
+
+	mu           sync.Mutex
+	failureCount int
+	backoffUntil time.Time
+}
+
+func (e *endpoint) healthy() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return time.Now().After(e.backoffUntil)
+}
+
+func (e *endpoint) recordFailure() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failureCount++
+	backoff := baseBackoff * time.Duration(1<<min(e.failureCount, 6))
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	e.backoffUntil = time.Now().Add(backoff)
+}
+
+func (e *endpoint) recordSuccess() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failureCount = 0
+	e.backoffUntil = time.Time{}
+}
+
+// Pool is a drop-in replacement for *rpc.Client that fans calls out across
+// multiple endpoints and caches read-only responses.
+type Pool struct {
+	endpoints []*endpoint
+	cache     *ttlCache
+	next      atomic.Uint64 // advances pick's position in the weighted rotation
+}
+
+// New builds a Pool from a list of RPC URLs, all weighted equally. Pass a
+// single URL for the old single-endpoint behavior.
+func New(urls []string) *Pool {
+	if len(urls) == 0 {
+		urls = []string{rpc.MainNetBeta_RPC}
+	}
+	p := &Pool{cache: newTTLCache()}
+	for _, url := range urls {
+		p.endpoints = append(p.endpoints, &endpoint{
+			url:    url,
+			weight: DefaultWeight,
+			client: rpc.New(url),
+		})
+	}
+	return p
+}
+
+// pick returns the next healthy endpoint in weighted round-robin order
+// (each endpoint comes up weight times per rotation), falling back to any
+// endpoint (ignoring health) if every endpoint is currently backed off.
+func (p *Pool) pick(exclude map[*endpoint]bool) *endpoint {
+	var candidates []*endpoint
+	totalWeight := 0
+	for _, e := range p.endpoints {
+		if exclude[e] || !e.healthy() {
+			continue
+		}
+		candidates = append(candidates, e)
+		totalWeight += e.weight
+	}
+	if len(candidates) == 0 {
+		for _, e := range p.endpoints {
+			if !exclude[e] {
+				candidates = append(candidates, e)
+				totalWeight += e.weight
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	r := int((p.next.Add(1) - 1) % uint64(totalWeight))
+	for _, e := range candidates {
+		r -= e.weight
+		if r < 0 {
+			return e
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// isRetryable reports whether err looks like a rate-limit or transient
+// server error worth failing over for.
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "429") ||
+		strings.Contains(msg, "too many requests") ||
+		strings.Contains(msg, "500") ||
+		strings.Contains(msg, "502") ||
+		strings.Contains(msg, "503") ||
+		strings.Contains(msg, "504")
+}
+
+// call runs fn against endpoints in weighted round-robin order, failing over on
+// retryable errors and applying exponential backoff to endpoints that
+// return one.
+func (p *Pool) call(fn func(*rpc.Client) error) error {
+	exclude := make(map[*endpoint]bool)
+	var lastErr error
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		e := p.pick(exclude)
+		if e == nil {
+			break
+		}
+
+		err := fn(e.client)
+		if err == nil {
+			e.recordSuccess()
+			return nil
+		}
+
+		lastErr = err
+		if !isRetryable(err) {
+			return err
+		}
+
+		log.Printf("rpcpool: endpoint %s failed (%v), failing over", e.url, err)
+		e.recordFailure()
+		exclude[e] = true
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("rpcpool: no healthy endpoints available")
+	}
+	return lastErr
+}
+
+// methodTTL returns how long a method's responses should be cached.
+// Account-level data changes slowly; holder snapshots and wallet balances
+// are closer to real-time and need much shorter TTLs.
+func methodTTL(method string) time.Duration {
+	switch method {
+	case "getAccountInfo":
+		return 2 * time.Minute
+	case "getAccountInfoBalance":
+		// Wallet SOL balances are read on every watch poll; a long TTL
+		// here would mean real balance movements go unnoticed for
+		// multiple poll cycles (see subscriptions.go's pollWalletSubscription).
+		return 5 * time.Second
+	case "getTokenLargestAccounts":
+		return 10 * time.Second
+	case "getSignaturesForAddress", "getTransaction":
+		// Transaction history is immutable once finalized.
+		return 10 * time.Minute
+	default:
+		return 5 * time.Second
+	}
+}
+
+func (p *Pool) GetAccountInfo(ctx context.Context, pubkey solana.PublicKey) (*rpc.GetAccountInfoResult, error) {
+	return p.getAccountInfo(ctx, "getAccountInfo", pubkey)
+}
+
+// GetAccountInfoForBalance fetches account info for the sole purpose of
+// reading its SOL balance (e.g. a watched wallet). It's cached separately
+// from GetAccountInfo, and with a much shorter TTL, so balance polling
+// isn't starved by the longer TTL used for mint metadata lookups.
+func (p *Pool) GetAccountInfoForBalance(ctx context.Context, pubkey solana.PublicKey) (*rpc.GetAccountInfoResult, error) {
+	return p.getAccountInfo(ctx, "getAccountInfoBalance", pubkey)
+}
+
+func (p *Pool) getAccountInfo(ctx context.Context, cacheMethod string, pubkey solana.PublicKey) (*rpc.GetAccountInfoResult, error) {
+	key := cacheKey(cacheMethod, pubkey.String())
+	if cached, ok := p.cache.get(key); ok {
+		return cached.(*rpc.GetAccountInfoResult), nil
+	}
+
+	var result *rpc.GetAccountInfoResult
+	err := p.call(func(c *rpc.Client) error {
+		var err error
+		result, err = c.GetAccountInfo(ctx, pubkey)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.cache.set(key, result, methodTTL(cacheMethod))
+	return result, nil
+}
+
+func (p *Pool) GetTokenLargestAccounts(ctx context.Context, pubkey solana.PublicKey, commitment rpc.CommitmentType) (*rpc.GetTokenLargestAccountsResult, error) {
+	key := cacheKey("getTokenLargestAccounts", pubkey.String(), string(commitment))
+	if cached, ok := p.cache.get(key); ok {
+		return cached.(*rpc.GetTokenLargestAccountsResult), nil
+	}
+
+	var result *rpc.GetTokenLargestAccountsResult
+	err := p.call(func(c *rpc.Client) error {
+		var err error
+		result, err = c.GetTokenLargestAccounts(ctx, pubkey, commitment)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.cache.set(key, result, methodTTL("getTokenLargestAccounts"))
+	return result, nil
+}
+
+func (p *Pool) GetSignaturesForAddressWithOpts(ctx context.Context, pubkey solana.PublicKey, opts *rpc.GetSignaturesForAddressOpts) ([]*rpc.TransactionSignature, error) {
+	// Build the key from opts' dereferenced fields rather than formatting
+	// opts itself — %+v on a struct with a *int Limit field prints the
+	// pointer's address, not its value, so two calls with identical limits
+	// (a fresh *int on every call, per earliestSignature) would never
+	// cache-hit against each other.
+	limit := 0
+	before, until, commitment := "", "", ""
+	if opts != nil {
+		if opts.Limit != nil {
+			limit = *opts.Limit
+		}
+		before = opts.Before.String()
+		until = opts.Until.String()
+		commitment = string(opts.Commitment)
+	}
+	key := cacheKey("getSignaturesForAddress", pubkey.String(), strconv.Itoa(limit), before, until, commitment)
+	if cached, ok := p.cache.get(key); ok {
+		return cached.([]*rpc.TransactionSignature), nil
+	}
+
+	var result []*rpc.TransactionSignature
+	err := p.call(func(c *rpc.Client) error {
+		var err error
+		result, err = c.GetSignaturesForAddressWithOpts(ctx, pubkey, opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.cache.set(key, result, methodTTL("getSignaturesForAddress"))
+	return result, nil
+}
+
+func (p *Pool) GetTransaction(ctx context.Context, signature solana.Signature, opts *rpc.GetTransactionOpts) (*rpc.GetTransactionResult, error) {
+	commitment := ""
+	if opts != nil {
+		commitment = string(opts.Commitment)
+	}
+	key := cacheKey("getTransaction", signature.String(), commitment)
+	if cached, ok := p.cache.get(key); ok {
+		return cached.(*rpc.GetTransactionResult), nil
+	}
+
+	var result *rpc.GetTransactionResult
+	err := p.call(func(c *rpc.Client) error {
+		var err error
+		result, err = c.GetTransaction(ctx, signature, opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.cache.set(key, result, methodTTL("getTransaction"))
+	return result, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gagliardetto/solana-go"
+	bolt "go.etcd.io/bbolt"
+)
+
+// SubscriptionKind distinguishes what a watch is tracking.
+type SubscriptionKind string
+
+const (
+	SubscriptionToken  SubscriptionKind = "token"
+	SubscriptionWallet SubscriptionKind = "wallet"
+)
+
+// maxSubscriptionsPerGuild caps how many `!watch`es a single guild can hold
+// so one busy server can't monopolize poller time.
+const maxSubscriptionsPerGuild = 25
+
+var subscriptionsBucket = []byte("subscriptions")
+
+// Subscription is a persisted `!watch` target. LastAnalysis/LastBalance hold
+// the most recent snapshot so the poller can diff against it.
+type Subscription struct {
+	GuildID      string           `json:"guild_id"`
+	ChannelID    string           `json:"channel_id"`
+	Address      string           `json:"address"`
+	Kind         SubscriptionKind `json:"kind"`
+	CreatedBy    string           `json:"created_by"`
+	CreatedAt    time.Time        `json:"created_at"`
+	LastAnalysis *TokenAnalysis   `json:"last_analysis,omitempty"`
+	LastBalance  float64          `json:"last_balance,omitempty"`
+}
+
+// scope is the namespace a subscription's key is grouped under: the guild
+// ID for a guild channel, or "dm/<channel>" for a DM. DMs have no GuildID,
+// so without this every DM watch from every user would share one global
+// namespace — a second user's /watch on an address someone else is already
+// watching in an unrelated DM would collide with it, and
+// maxSubscriptionsPerGuild would cap DM watches bot-wide instead of
+// per-conversation.
+func scope(guildID, channelID string) string {
+	if guildID == "" {
+		return "dm/" + channelID
+	}
+	return guildID
+}
+
+func (sub *Subscription) key() []byte {
+	return []byte(scope(sub.GuildID, sub.ChannelID) + "/" + sub.Address)
+}
+
+// SubscriptionStore persists subscriptions in a bbolt database so watches
+// survive bot restarts.
+type SubscriptionStore struct {
+	db *bolt.DB
+}
+
+func openSubscriptionStore(path string) (*SubscriptionStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(subscriptionsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SubscriptionStore{db: db}, nil
+}
+
+func (store *SubscriptionStore) Close() error {
+	return store.db.Close()
+}
+
+func (store *SubscriptionStore) Add(sub *Subscription) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(sub)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(subscriptionsBucket).Put(sub.key(), data)
+	})
+}
+
+func (store *SubscriptionStore) Remove(guildID, channelID, address string) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(subscriptionsBucket).Delete([]byte(scope(guildID, channelID) + "/" + address))
+	})
+}
+
+func (store *SubscriptionStore) Update(sub *Subscription) error {
+	return store.Add(sub)
+}
+
+// ForScope returns every subscription registered in a guild, or (when
+// guildID is "") in a single DM channel — see scope().
+func (store *SubscriptionStore) ForScope(guildID, channelID string) ([]*Subscription, error) {
+	var subs []*Subscription
+	err := store.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(subscriptionsBucket).Cursor()
+		prefix := []byte(scope(guildID, channelID) + "/")
+		for k, v := c.Seek(prefix); k != nil && strings.HasPrefix(string(k), string(prefix)); k, v = c.Next() {
+			var sub Subscription
+			if err := json.Unmarshal(v, &sub); err != nil {
+				return err
+			}
+			subs = append(subs, &sub)
+		}
+		return nil
+	})
+	return subs, err
+}
+
+// All returns every subscription across every guild, for the poller.
+func (store *SubscriptionStore) All() ([]*Subscription, error) {
+	var subs []*Subscription
+	err := store.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(subscriptionsBucket).ForEach(func(k, v []byte) error {
+			var sub Subscription
+			if err := json.Unmarshal(v, &sub); err != nil {
+				return err
+			}
+			subs = append(subs, &sub)
+			return nil
+		})
+	})
+	return subs, err
+}
+
+// handleCommand is the entry point for the `!`-prefixed command router. It
+// returns true if the message was recognized as a command, so messageCreate
+// can skip the URL/raw-address auto-detect fallback.
+func handleCommand(s *discordgo.Session, m *discordgo.MessageCreate) bool {
+	fields := strings.Fields(m.Content)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "!") {
+		return false
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "!watch":
+		handleWatchCommand(s, m, fields[1:])
+	case "!unwatch":
+		handleUnwatchCommand(s, m, fields[1:])
+	case "!list":
+		handleListCommand(s, m)
+	default:
+		return false
+	}
+	return true
+}
+
+func handleWatchCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) != 1 || !validateSolanaAddress(args[0]) {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!watch <address>`")
+		return
+	}
+	address := args[0]
+
+	existing, err := subStore.ForScope(m.GuildID, m.ChannelID)
+	if err != nil {
+		log.Printf("Error listing subscriptions for guild %s: %v", m.GuildID, err)
+		s.ChannelMessageSend(m.ChannelID, "Something went wrong, try again later.")
+		return
+	}
+	if len(existing) >= maxSubscriptionsPerGuild {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("This server already has the maximum of %d watches.", maxSubscriptionsPerGuild))
+		return
+	}
+	for _, sub := range existing {
+		if sub.Address == address {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("`%s` is already being watched in <#%s>.", truncateAddress(address), sub.ChannelID))
+			return
+		}
+	}
+
+	sub := &Subscription{
+		GuildID:   m.GuildID,
+		ChannelID: m.ChannelID,
+		Address:   address,
+		CreatedBy: m.Author.ID,
+		CreatedAt: time.Now(),
+	}
+
+	if analysis, err := analyzeToken(address); err == nil {
+		sub.Kind = SubscriptionToken
+		sub.LastAnalysis = analysis
+	} else if balance, err := getWalletBalance(address); err == nil {
+		sub.Kind = SubscriptionWallet
+		sub.LastBalance = balance
+	} else {
+		s.ChannelMessageSend(m.ChannelID, "Could not resolve that address as a token or wallet.")
+		return
+	}
+
+	if err := subStore.Add(sub); err != nil {
+		log.Printf("Error saving subscription: %v", err)
+		s.ChannelMessageSend(m.ChannelID, "Something went wrong, try again later.")
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Now watching `%s` in this channel.", truncateAddress(address)))
+}
+
+func handleUnwatchCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) != 1 || !validateSolanaAddress(args[0]) {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!unwatch <address>`")
+		return
+	}
+
+	if err := subStore.Remove(m.GuildID, m.ChannelID, args[0]); err != nil {
+		log.Printf("Error removing subscription: %v", err)
+		s.ChannelMessageSend(m.ChannelID, "Something went wrong, try again later.")
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Stopped watching `%s`.", truncateAddress(args[0])))
+}
+
+func handleListCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
+	subs, err := subStore.ForScope(m.GuildID, m.ChannelID)
+	if err != nil {
+		log.Printf("Error listing subscriptions for guild %s: %v", m.GuildID, err)
+		s.ChannelMessageSend(m.ChannelID, "Something went wrong, try again later.")
+		return
+	}
+	if len(subs) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "Nothing is being watched in this server yet.")
+		return
+	}
+
+	var sb strings.Builder
+	for i, sub := range subs {
+		fmt.Fprintf(&sb, "%d. `%s` (%s) in <#%s>\n", i+1, truncateAddress(sub.Address), sub.Kind, sub.ChannelID)
+	}
+	s.ChannelMessageSend(m.ChannelID, sb.String())
+}
+
+func getWalletBalance(address string) (float64, error) {
+	pubkey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return 0, err
+	}
+	acc, err := client.GetAccountInfoForBalance(context.Background(), pubkey)
+	if err != nil {
+		return 0, err
+	}
+	if acc == nil || acc.Value == nil {
+		return 0, fmt.Errorf("account not found")
+	}
+	return float64(acc.Value.Lamports) / 1e9, nil
+}
+
+// pollSubscriptions runs for the lifetime of the bot, periodically diffing
+// every watched address against its last-known state and posting an embed
+// when something notable changed.
+func pollSubscriptions(s *discordgo.Session, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		subs, err := subStore.All()
+		if err != nil {
+			log.Printf("Error loading subscriptions: %v", err)
+			continue
+		}
+
+		for _, sub := range subs {
+			switch sub.Kind {
+			case SubscriptionToken:
+				pollTokenSubscription(s, sub)
+			case SubscriptionWallet:
+				pollWalletSubscription(s, sub)
+			}
+		}
+	}
+}
+
+// balanceAlertThresholdSOL is the minimum SOL balance movement worth alerting on.
+const balanceAlertThresholdSOL = 1.0
+
+func pollTokenSubscription(s *discordgo.Session, sub *Subscription) {
+	analysis, err := analyzeToken(sub.Address)
+	if err != nil {
+		log.Printf("Error refreshing watched token %s: %v", sub.Address, err)
+		return
+	}
+
+	if tokenAnalysisChanged(sub.LastAnalysis, analysis) {
+		embed := buildTokenAnalysisEmbed(sub.Address, analysis, 0, true)
+		embed.Title = "Watch Update: " + embed.Title
+		s.ChannelMessageSendEmbed(sub.ChannelID, embed)
+	}
+
+	sub.LastAnalysis = analysis
+	if err := subStore.Update(sub); err != nil {
+		log.Printf("Error persisting subscription update for %s: %v", sub.Address, err)
+	}
+}
+
+func pollWalletSubscription(s *discordgo.Session, sub *Subscription) {
+	balance, err := getWalletBalance(sub.Address)
+	if err != nil {
+		log.Printf("Error refreshing watched wallet %s: %v", sub.Address, err)
+		return
+	}
+
+	if diff := balance - sub.LastBalance; diff > balanceAlertThresholdSOL || diff < -balanceAlertThresholdSOL {
+		s.ChannelMessageSend(sub.ChannelID, fmt.Sprintf(
+			"SOL balance for `%s` moved from %.4f to %.4f SOL.",
+			truncateAddress(sub.Address), sub.LastBalance, balance))
+	}
+
+	sub.LastBalance = balance
+	if err := subStore.Update(sub); err != nil {
+		log.Printf("Error persisting subscription update for %s: %v", sub.Address, err)
+	}
+}
+
+// tokenAnalysisChanged reports whether a refreshed analysis differs from the
+// last-known one in a way worth alerting a watcher about: a new top holder,
+// a top-holder rebalance beyond topHolderRebalanceThreshold, a supply
+// change, or a mint or freeze authority change.
+const topHolderRebalanceThreshold = 5.0 // percentage points
+
+func tokenAnalysisChanged(prev, next *TokenAnalysis) bool {
+	if prev == nil || next == nil {
+		return false
+	}
+	if prev.TotalSupply != next.TotalSupply {
+		return true
+	}
+	if prev.MintAuthority != next.MintAuthority {
+		return true
+	}
+	if prev.FreezeAuthority != next.FreezeAuthority {
+		return true
+	}
+
+	prevHolders := make(map[string]float64, len(prev.TopHolders))
+	for _, h := range prev.TopHolders {
+		prevHolders[h.Address] = h.Percent
+	}
+
+	for _, h := range next.TopHolders {
+		prevPercent, known := prevHolders[h.Address]
+		if !known {
+			return true // new entrant to the top holder set
+		}
+		if h.Percent-prevPercent > topHolderRebalanceThreshold || prevPercent-h.Percent > topHolderRebalanceThreshold {
+			return true
+		}
+	}
+
+	return false
+}